@@ -0,0 +1,77 @@
+// Package cache holds the on-disk response cache shared by yttranscriptor
+// and mediadownloader, so both clients can short-circuit idempotent GETs
+// (transcripts keyed by videoID+lang, channel listings keyed by
+// channelID+page) without burning RapidAPI quota.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache stores raw response bodies keyed by request URL, alongside the time
+// they expire at. Implementations need not enforce the expiry themselves;
+// callers compare the returned time against time.Now() to decide freshness,
+// which lets them keep a stale entry around for conditional (ETag) requests.
+type Cache interface {
+	Get(key string) (val []byte, expiresAt time.Time, ok bool)
+	Put(key string, val []byte, expiresAt time.Time) error
+}
+
+// FileCache is a Cache backed by one file per key under the directory
+// passed to NewFileCache.
+type FileCache struct {
+	dir string
+}
+
+// NewFileCache returns a FileCache that stores entries under dir, creating
+// it on first write.
+func NewFileCache(dir string) *FileCache {
+	return &FileCache{dir: dir}
+}
+
+type fileCacheEntry struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	Val       []byte    `json:"val"`
+}
+
+func (f *FileCache) pathFor(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (f *FileCache) Get(key string) ([]byte, time.Time, bool) {
+	data, err := os.ReadFile(f.pathFor(key))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+
+	var entry fileCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, time.Time{}, false
+	}
+
+	return entry.Val, entry.ExpiresAt, true
+}
+
+func (f *FileCache) Put(key string, val []byte, expiresAt time.Time) error {
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	data, err := json.Marshal(fileCacheEntry{ExpiresAt: expiresAt, Val: val})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := os.WriteFile(f.pathFor(key), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return nil
+}
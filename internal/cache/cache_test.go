@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFileCacheRoundTrip(t *testing.T) {
+	c := NewFileCache(t.TempDir())
+
+	expiresAt := time.Now().Add(time.Hour)
+	if err := c.Put("key", []byte("body"), expiresAt); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	val, got, ok := c.Get("key")
+	if !ok {
+		t.Fatal("Get: ok = false, want true")
+	}
+	if string(val) != "body" {
+		t.Errorf("val = %q, want %q", val, "body")
+	}
+	if !got.Equal(expiresAt) {
+		t.Errorf("expiresAt = %v, want %v", got, expiresAt)
+	}
+}
+
+func TestFileCacheMissingKey(t *testing.T) {
+	c := NewFileCache(t.TempDir())
+
+	if _, _, ok := c.Get("missing"); ok {
+		t.Fatal("Get: ok = true, want false for missing key")
+	}
+}
+
+func TestFileCacheExpiryIsCallerEnforced(t *testing.T) {
+	c := NewFileCache(t.TempDir())
+
+	past := time.Now().Add(-time.Hour)
+	if err := c.Put("key", []byte("stale"), past); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// Get itself does not enforce expiry; callers compare against
+	// time.Now(), which lets them reuse a stale entry for ETag
+	// revalidation.
+	val, got, ok := c.Get("key")
+	if !ok {
+		t.Fatal("Get: ok = false, want true")
+	}
+	if string(val) != "stale" {
+		t.Errorf("val = %q, want %q", val, "stale")
+	}
+	if !got.Before(time.Now()) {
+		t.Errorf("expiresAt = %v, want before now", got)
+	}
+}
+
+func TestFileCacheOverwrite(t *testing.T) {
+	c := NewFileCache(t.TempDir())
+
+	first := time.Now().Add(time.Hour)
+	second := time.Now().Add(2 * time.Hour)
+
+	if err := c.Put("key", []byte("v1"), first); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := c.Put("key", []byte("v2"), second); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	val, got, ok := c.Get("key")
+	if !ok {
+		t.Fatal("Get: ok = false, want true")
+	}
+	if string(val) != "v2" {
+		t.Errorf("val = %q, want %q", val, "v2")
+	}
+	if !got.Equal(second) {
+		t.Errorf("expiresAt = %v, want %v", got, second)
+	}
+}
@@ -0,0 +1,209 @@
+// Package httpx holds the HTTP-retry plumbing shared by yttranscriptor and
+// mediadownloader so both clients handle RapidAPI's 429/5xx responses the
+// same way.
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Typed errors returned by Do so callers can branch with errors.Is instead
+// of matching on status codes or response bodies.
+var (
+	ErrRateLimited   = errors.New("httpx: rate limited")
+	ErrNotFound      = errors.New("httpx: not found")
+	ErrQuotaExceeded = errors.New("httpx: quota exceeded")
+	ErrTransient     = errors.New("httpx: transient error")
+	ErrPermanent     = errors.New("httpx: permanent error")
+)
+
+// Logger is satisfied by *log.Logger, so callers can pass the standard
+// logger straight through WithLogger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Config controls Do's retry behavior.
+type Config struct {
+	// MaxRetries is the number of retries after the initial attempt.
+	MaxRetries int
+	// MaxRetryAfter caps how long Do will sleep for a server-provided
+	// Retry-After value. Defaults to 30s.
+	MaxRetryAfter time.Duration
+	// Logger, if set, receives a line per retry.
+	Logger Logger
+	// NotModifiedBody, if set, is returned as-is when the server responds
+	// 304 Not Modified, for callers doing a conditional GET against a
+	// cached body with If-None-Match.
+	NotModifiedBody []byte
+	// ResponseHeader, if set, is populated with the header of whichever
+	// response Do returns a body for (200, or 304 when NotModifiedBody is
+	// set).
+	ResponseHeader *http.Header
+}
+
+const defaultMaxRetryAfter = 30 * time.Second
+
+// Do executes req with client and returns the response body once it sees a
+// 200. It retries 429/503 responses honoring Retry-After (capped at
+// cfg.MaxRetryAfter), and retries other 5xx responses and net.Error timeouts
+// with exponential backoff and jitter, up to cfg.MaxRetries times. Any other
+// non-200 response, or the final failed attempt, is returned as one of the
+// typed errors in this package. Backoff sleeps between retries honor
+// req.Context(), so a cancelled context interrupts a retry wait instead of
+// running it to completion.
+func Do(client *http.Client, req *http.Request, cfg Config) ([]byte, error) {
+	maxRetryAfter := cfg.MaxRetryAfter
+	if maxRetryAfter <= 0 {
+		maxRetryAfter = defaultMaxRetryAfter
+	}
+
+	// Buffer the body (if any) so it can be replayed across retries.
+	var bodyBytes []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer request body: %w", err)
+		}
+		req.Body.Close()
+		bodyBytes = b
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("%w: %v", ErrTransient, err)
+			if !isRetryableNetErr(err) || attempt == cfg.MaxRetries {
+				return nil, lastErr
+			}
+			Logf(cfg.Logger, "httpx: request error (attempt %d/%d): %v", attempt+1, cfg.MaxRetries+1, err)
+			if sErr := sleep(req.Context(), backoff(attempt)); sErr != nil {
+				return nil, sErr
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		if res.StatusCode == http.StatusNotModified && cfg.NotModifiedBody != nil {
+			if cfg.ResponseHeader != nil {
+				*cfg.ResponseHeader = res.Header
+			}
+			return cfg.NotModifiedBody, nil
+		}
+
+		if res.StatusCode == http.StatusOK {
+			if cfg.ResponseHeader != nil {
+				*cfg.ResponseHeader = res.Header
+			}
+			return body, nil
+		}
+
+		lastErr = classify(res.StatusCode, body)
+
+		retryable := res.StatusCode == http.StatusTooManyRequests ||
+			res.StatusCode == http.StatusServiceUnavailable ||
+			res.StatusCode >= 500
+		if !retryable || attempt == cfg.MaxRetries {
+			return nil, lastErr
+		}
+
+		wait := backoff(attempt)
+		if ra, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			wait = ra
+			if wait > maxRetryAfter {
+				wait = maxRetryAfter
+			}
+		}
+
+		Logf(cfg.Logger, "httpx: retryable status %d (attempt %d/%d), waiting %s", res.StatusCode, attempt+1, cfg.MaxRetries+1, wait)
+		if sErr := sleep(req.Context(), wait); sErr != nil {
+			return nil, sErr
+		}
+	}
+
+	return nil, lastErr
+}
+
+func classify(status int, body []byte) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", ErrRateLimited, string(body))
+	case status == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrNotFound, string(body))
+	case status == http.StatusForbidden || status == http.StatusPaymentRequired:
+		return fmt.Errorf("%w: %s", ErrQuotaExceeded, string(body))
+	case status == http.StatusServiceUnavailable || status >= 500:
+		return fmt.Errorf("%w: %s", ErrTransient, string(body))
+	default:
+		return fmt.Errorf("%w: http status code is not ok: %s", ErrPermanent, string(body))
+	}
+}
+
+func isRetryableNetErr(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns an exponential delay (250ms base) plus full jitter.
+func backoff(attempt int) time.Duration {
+	base := time.Duration(1<<attempt) * 250 * time.Millisecond
+	return base + time.Duration(rand.Int63n(int64(base)+1))
+}
+
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// sleep waits for d, or returns ctx.Err() early if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Logf writes a log line through l, if set. It's exported so callers that
+// thread a Logger through their own options (as both clients in this module
+// do) can log through the same helper instead of each pasting their own.
+func Logf(l Logger, format string, args ...interface{}) {
+	if l != nil {
+		l.Printf(format, args...)
+	}
+}
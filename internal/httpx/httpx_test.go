@@ -0,0 +1,124 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDoSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", "abc")
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var hdr http.Header
+	body, err := Do(http.DefaultClient, mustGet(t, srv.URL), Config{ResponseHeader: &hdr})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want %q", body, "ok")
+	}
+	if hdr.Get("ETag") != "abc" {
+		t.Errorf("ETag = %q, want %q", hdr.Get("ETag"), "abc")
+	}
+}
+
+func TestDoNotModified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	body, err := Do(http.DefaultClient, mustGet(t, srv.URL), Config{NotModifiedBody: []byte("cached")})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if string(body) != "cached" {
+		t.Errorf("body = %q, want %q", body, "cached")
+	}
+}
+
+func TestDoClassifiesNonRetryableErrors(t *testing.T) {
+	cases := []struct {
+		status  int
+		wantErr error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusForbidden, ErrQuotaExceeded},
+		{http.StatusPaymentRequired, ErrQuotaExceeded},
+		{http.StatusBadRequest, ErrPermanent},
+	}
+
+	for _, tc := range cases {
+		var calls int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(tc.status)
+		}))
+
+		_, err := Do(http.DefaultClient, mustGet(t, srv.URL), Config{MaxRetries: 2})
+		srv.Close()
+
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("status %d: err = %v, want %v", tc.status, err, tc.wantErr)
+		}
+		if calls != 1 {
+			t.Errorf("status %d: calls = %d, want 1 (non-retryable)", tc.status, calls)
+		}
+	}
+}
+
+func TestDoRetriesRateLimitedThenSucceeds(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	body, err := Do(http.DefaultClient, mustGet(t, srv.URL), Config{MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want %q", body, "ok")
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestDoExhaustsRetriesOn5xx(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := Do(http.DefaultClient, mustGet(t, srv.URL), Config{MaxRetries: 1})
+	if !errors.Is(err, ErrTransient) {
+		t.Fatalf("err = %v, want %v", err, ErrTransient)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (initial + 1 retry)", calls)
+	}
+}
+
+func mustGet(t *testing.T, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	return req
+}
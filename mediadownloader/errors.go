@@ -0,0 +1,26 @@
+package mediadownloader
+
+import "github.com/bjornpagen/youtube-apis/internal/httpx"
+
+// Sentinel errors returned by Client, aliased from the httpx package so a
+// single errors.Is check works regardless of which client in this module
+// produced the error.
+var (
+	// ErrRateLimited indicates the API is temporarily rate limiting
+	// requests.
+	ErrRateLimited = httpx.ErrRateLimited
+
+	// ErrNotFound indicates the channel or video could not be found.
+	ErrNotFound = httpx.ErrNotFound
+
+	// ErrQuotaExceeded indicates the API's usage quota has been exhausted.
+	ErrQuotaExceeded = httpx.ErrQuotaExceeded
+
+	// ErrTransient indicates a retryable failure, such as a network error
+	// or a 5xx response.
+	ErrTransient = httpx.ErrTransient
+
+	// ErrPermanent indicates a non-retryable failure other than the cases
+	// above.
+	ErrPermanent = httpx.ErrPermanent
+)
@@ -1,12 +1,17 @@
 package mediadownloader
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"time"
 
+	"github.com/bjornpagen/youtube-apis/internal/cache"
+	"github.com/bjornpagen/youtube-apis/internal/httpx"
 	"go.uber.org/ratelimit"
 )
 
@@ -16,6 +21,10 @@ type options struct {
 	host       string
 	rateLimit  *ratelimit.Limiter
 	httpClient *http.Client
+	maxRetries int
+	logger     httpx.Logger
+	cache      cache.Cache
+	cacheTTL   time.Duration
 }
 
 func WithHost(host string) Option {
@@ -45,6 +54,40 @@ func WithHttpClient(hc http.Client) Option {
 	}
 }
 
+// WithMaxRetries sets how many times a request is retried after a 429/5xx
+// response or a network timeout, with exponential backoff and jitter
+// between attempts (and Retry-After honored where the response provides it).
+// An n of 0 leaves the default of 3 retries in place.
+func WithMaxRetries(n int) Option {
+	return func(option *options) error {
+		option.maxRetries = n
+		return nil
+	}
+}
+
+// WithLogger sets a logger to receive a line per retry attempt. *log.Logger
+// satisfies this interface.
+func WithLogger(logger httpx.Logger) Option {
+	return func(option *options) error {
+		option.logger = logger
+		return nil
+	}
+}
+
+// WithCache makes GetChannelVideosPage (and so GetChannelVideos and
+// WalkChannelVideos) serve fresh entries from c for up to ttl before
+// re-fetching, using If-None-Match to revalidate stale entries cheaply when
+// the server supports it. Channel listings are idempotent per (channelID,
+// page, contentType), so this can slash RapidAPI quota usage for callers
+// that repeatedly hit the same channels.
+func WithCache(c cache.Cache, ttl time.Duration) Option {
+	return func(option *options) error {
+		option.cache = c
+		option.cacheTTL = ttl
+		return nil
+	}
+}
+
 type Client struct {
 	apiKey  string
 	options *options
@@ -72,6 +115,10 @@ func New(apiKey string, opts ...Option) (*Client, error) {
 		o.httpClient = http.DefaultClient
 	}
 
+	if o.maxRetries == 0 {
+		o.maxRetries = 3
+	}
+
 	return &Client{
 		apiKey:  apiKey,
 		options: o,
@@ -81,8 +128,10 @@ func New(apiKey string, opts ...Option) (*Client, error) {
 type getChannelVideosOption func(option *getChannelVideosOptions) error
 
 type getChannelVideosOptions struct {
-	lang        string
-	contentType ContentType
+	lang           string
+	contentType    ContentType
+	maxVideos      int
+	publishedAfter time.Time
 }
 
 type ContentType string
@@ -108,6 +157,27 @@ func WithContentType(contentType ContentType) getChannelVideosOption {
 	}
 }
 
+// WithMaxVideos bounds WalkChannelVideos to at most n videos. It has no
+// effect on GetChannelVideos or GetChannelVideosPage, which always return a
+// single page.
+func WithMaxVideos(n int) getChannelVideosOption {
+	return func(option *getChannelVideosOptions) error {
+		option.maxVideos = n
+		return nil
+	}
+}
+
+// WithPublishedAfter stops WalkChannelVideos once it reaches a video
+// published before t, based on a best-effort parse of the channel's relative
+// PublishedTimeText. It has no effect on GetChannelVideos or
+// GetChannelVideosPage, which always return a single page.
+func WithPublishedAfter(t time.Time) getChannelVideosOption {
+	return func(option *getChannelVideosOptions) error {
+		option.publishedAfter = t
+		return nil
+	}
+}
+
 type getChannelVideosResponse struct {
 	Status    bool    `json:"status"`
 	NextToken string  `json:"nextToken"`
@@ -132,12 +202,23 @@ type Thumbnail struct {
 	Moving bool   `json:"moving"`
 }
 
-func (c *Client) GetChannelVideos(channelID string, opts ...getChannelVideosOption) ([]Video, error) {
+// GetChannelVideos returns the first page of a channel's videos. To follow
+// pagination, use GetChannelVideosPage or WalkChannelVideos.
+func (c *Client) GetChannelVideos(ctx context.Context, channelID string, opts ...getChannelVideosOption) ([]Video, error) {
+	items, _, err := c.GetChannelVideosPage(ctx, channelID, "", opts...)
+	return items, err
+}
+
+// GetChannelVideosPage returns a single page of a channel's videos starting
+// at pageToken (pass "" for the first page) along with the token for the
+// next page, which is "" once exhausted. ctx bounds both the request and any
+// retry backoff.
+func (c *Client) GetChannelVideosPage(ctx context.Context, channelID, pageToken string, opts ...getChannelVideosOption) ([]Video, string, error) {
 	o := &getChannelVideosOptions{}
 	for _, opt := range opts {
 		err := opt(o)
 		if err != nil {
-			return nil, fmt.Errorf("bad option: %w", err)
+			return nil, "", fmt.Errorf("bad option: %w", err)
 		}
 	}
 
@@ -148,37 +229,157 @@ func (c *Client) GetChannelVideos(channelID string, opts ...getChannelVideosOpti
 		o.contentType = ContentTypeVideos
 	}
 
-	url := fmt.Sprintf("https://%s/v2/channel/videos?channelId=%s&lang=%s", c.options.host, channelID, o.lang)
+	q := url.Values{}
+	q.Set("channelId", channelID)
+	q.Set("lang", o.lang)
+	if pageToken != "" {
+		q.Set("nextToken", pageToken)
+	}
+	reqURL := fmt.Sprintf("https://%s/v2/channel/videos?%s", c.options.host, q.Encode())
+
+	if c.options.cache != nil {
+		if val, expiresAt, ok := c.options.cache.Get(reqURL); ok && time.Now().Before(expiresAt) {
+			response := &getChannelVideosResponse{}
+			if err := json.Unmarshal(val, response); err == nil {
+				return response.Items, response.NextToken, nil
+			}
+		}
+	}
 
 	(*c.options.rateLimit).Take()
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Add("X-RapidAPI-Key", c.apiKey)
 	req.Header.Add("X-RapidAPI-Host", c.options.host)
 
-	res, err := c.options.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	var notModifiedBody []byte
+	if c.options.cache != nil {
+		if etag, _, ok := c.options.cache.Get(reqURL + "#etag"); ok {
+			req.Header.Set("If-None-Match", string(etag))
+			notModifiedBody, _, _ = c.options.cache.Get(reqURL)
+		}
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
+	var respHeader http.Header
+	body, err := httpx.Do(c.options.httpClient, req, httpx.Config{
+		MaxRetries:      c.options.maxRetries,
+		Logger:          c.options.logger,
+		NotModifiedBody: notModifiedBody,
+		ResponseHeader:  &respHeader,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, "", err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status code is not ok: %s", string(body))
+	if c.options.cache != nil {
+		expiresAt := time.Now().Add(c.options.cacheTTL)
+		if err := c.options.cache.Put(reqURL, body, expiresAt); err != nil {
+			httpx.Logf(c.options.logger, "mediadownloader: cache put failed: %v", err)
+		}
+		if etag := respHeader.Get("ETag"); etag != "" {
+			if err := c.options.cache.Put(reqURL+"#etag", []byte(etag), expiresAt); err != nil {
+				httpx.Logf(c.options.logger, "mediadownloader: cache put failed: %v", err)
+			}
+		}
 	}
 
 	response := &getChannelVideosResponse{}
 	err = json.Unmarshal(body, response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+		return nil, "", fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return response.Items, response.NextToken, nil
+}
+
+// WalkChannelVideos follows nextToken across the full history of a channel,
+// invoking fn for each video. It stops at ctx cancellation, at
+// WithMaxVideos(n), at the first video older than WithPublishedAfter(t), or
+// as soon as fn returns an error, and returns that error (nil on normal
+// exhaustion).
+func (c *Client) WalkChannelVideos(ctx context.Context, channelID string, fn func(Video) error, opts ...getChannelVideosOption) error {
+	o := &getChannelVideosOptions{}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return fmt.Errorf("bad option: %w", err)
+		}
+	}
+
+	pageToken := ""
+	seen := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		items, nextToken, err := c.GetChannelVideosPage(ctx, channelID, pageToken, opts...)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range items {
+			if !o.publishedAfter.IsZero() {
+				if published, ok := parsePublishedTimeText(v.PublishedTimeText); ok && published.Before(o.publishedAfter) {
+					return nil
+				}
+			}
+
+			if err := fn(v); err != nil {
+				return err
+			}
+
+			seen++
+			if o.maxVideos > 0 && seen >= o.maxVideos {
+				return nil
+			}
+		}
+
+		if nextToken == "" {
+			return nil
+		}
+		pageToken = nextToken
+	}
+}
+
+var publishedTimeTextRe = regexp.MustCompile(`(\d+)\s+(second|minute|hour|day|week|month|year)s?\s+ago`)
+
+// parsePublishedTimeText best-effort parses YouTube's relative publish-time
+// strings (e.g. "3 days ago", "Streamed 2 weeks ago", "Premiered 1 year ago")
+// into an absolute time relative to now. ok is false if text doesn't match
+// the expected shape.
+func parsePublishedTimeText(text string) (t time.Time, ok bool) {
+	m := publishedTimeTextRe.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var d time.Duration
+	switch m[2] {
+	case "second":
+		d = time.Duration(n) * time.Second
+	case "minute":
+		d = time.Duration(n) * time.Minute
+	case "hour":
+		d = time.Duration(n) * time.Hour
+	case "day":
+		d = time.Duration(n) * 24 * time.Hour
+	case "week":
+		d = time.Duration(n) * 7 * 24 * time.Hour
+	case "month":
+		d = time.Duration(n) * 30 * 24 * time.Hour
+	case "year":
+		d = time.Duration(n) * 365 * 24 * time.Hour
+	default:
+		return time.Time{}, false
 	}
 
-	return response.Items, nil
+	return time.Now().Add(-d), true
 }
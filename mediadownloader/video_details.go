@@ -0,0 +1,165 @@
+package mediadownloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bjornpagen/youtube-apis/internal/httpx"
+)
+
+// jsonNumber unmarshals a JSON field as an integer whether the API sends it
+// as a number or, as this vendor family sometimes does (see
+// GetTranscriptResponse.LengthInSeconds), as a numeric string.
+type jsonNumber int64
+
+func (n *jsonNumber) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		*n = 0
+		return nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("jsonNumber: %w", err)
+	}
+
+	*n = jsonNumber(v)
+	return nil
+}
+
+// Format describes one downloadable audio or video stream for a video.
+type Format struct {
+	Itag          jsonNumber `json:"itag"`
+	MimeType      string     `json:"mimeType"`
+	Bitrate       jsonNumber `json:"bitrate"`
+	ContentLength jsonNumber `json:"contentLength"`
+	URL           string     `json:"url"`
+	Width         jsonNumber `json:"width,omitempty"`
+	Height        jsonNumber `json:"height,omitempty"`
+	AudioQuality  string     `json:"audioQuality,omitempty"`
+	QualityLabel  string     `json:"qualityLabel,omitempty"`
+}
+
+// VideoDetails holds metadata and the available download formats for a
+// video.
+type VideoDetails struct {
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	LengthSeconds   jsonNumber `json:"lengthSeconds"`
+	Formats         []Format   `json:"formats"`
+	AdaptiveFormats []Format   `json:"adaptiveFormats"`
+}
+
+type getVideoDetailsResponse struct {
+	Status bool `json:"status"`
+	VideoDetails
+}
+
+// GetVideoDetails fetches metadata and available audio/video formats for a
+// video.
+func (c *Client) GetVideoDetails(videoID string) (*VideoDetails, error) {
+	url := fmt.Sprintf("https://%s/v2/video/details?videoId=%s", c.options.host, videoID)
+
+	(*c.options.rateLimit).Take()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Add("X-RapidAPI-Key", c.apiKey)
+	req.Header.Add("X-RapidAPI-Host", c.options.host)
+
+	body, err := httpx.Do(c.options.httpClient, req, httpx.Config{
+		MaxRetries: c.options.maxRetries,
+		Logger:     c.options.logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &getVideoDetailsResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return &response.VideoDetails, nil
+}
+
+// StreamFormat opens format's underlying media URL and returns a reader the
+// caller can pipe into ffmpeg, an S3 multipart upload, or similar. offset is
+// the first byte to request; length is the number of bytes to request, or 0
+// to request through the end of the resource. This lets callers issue
+// parallel ranged reads of the same format, e.g. for an S3 multipart
+// upload. The caller must close the returned reader. The returned int64 is
+// the stream's content length, or -1 if unknown.
+func (c *Client) StreamFormat(ctx context.Context, format Format, offset, length int64) (io.ReadCloser, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", format.URL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if length > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	res, err := c.options.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		return nil, 0, fmt.Errorf("http status code is not ok: %s", string(body))
+	}
+
+	return res.Body, res.ContentLength, nil
+}
+
+// SelectBestAudio returns the highest-bitrate audio-only format, if any.
+func SelectBestAudio(details *VideoDetails) (Format, bool) {
+	var best Format
+	found := false
+
+	for _, f := range details.AdaptiveFormats {
+		if !strings.HasPrefix(f.MimeType, "audio/") {
+			continue
+		}
+		if !found || f.Bitrate > best.Bitrate {
+			best = f
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// SelectBestVideo returns the highest-resolution video-only format no taller
+// than maxHeight (or any height if maxHeight is 0), breaking ties by
+// bitrate.
+func SelectBestVideo(details *VideoDetails, maxHeight int) (Format, bool) {
+	var best Format
+	found := false
+
+	for _, f := range details.AdaptiveFormats {
+		if !strings.HasPrefix(f.MimeType, "video/") {
+			continue
+		}
+		if maxHeight > 0 && int(f.Height) > maxHeight {
+			continue
+		}
+		if !found || f.Height > best.Height || (f.Height == best.Height && f.Bitrate > best.Bitrate) {
+			best = f
+			found = true
+		}
+	}
+
+	return best, found
+}
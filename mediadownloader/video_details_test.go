@@ -0,0 +1,127 @@
+package mediadownloader
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFormatUnmarshalNumericAsNumber(t *testing.T) {
+	var f Format
+	if err := json.Unmarshal([]byte(`{"itag":140,"bitrate":128000,"contentLength":3000000,"width":1920,"height":1080}`), &f); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if f.Itag != 140 || f.Bitrate != 128000 || f.ContentLength != 3000000 || f.Width != 1920 || f.Height != 1080 {
+		t.Errorf("got %+v", f)
+	}
+}
+
+func TestFormatUnmarshalNumericAsString(t *testing.T) {
+	var f Format
+	if err := json.Unmarshal([]byte(`{"itag":"140","bitrate":"128000","contentLength":"3000000"}`), &f); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if f.Itag != 140 || f.Bitrate != 128000 || f.ContentLength != 3000000 {
+		t.Errorf("got %+v", f)
+	}
+}
+
+func TestFormatUnmarshalNullIsZero(t *testing.T) {
+	// Audio-only adaptive formats commonly report height/width as null.
+	var f Format
+	if err := json.Unmarshal([]byte(`{"itag":140,"mimeType":"audio/mp4","height":null,"width":null}`), &f); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if f.Height != 0 || f.Width != 0 {
+		t.Errorf("got height=%d width=%d, want 0, 0", f.Height, f.Width)
+	}
+}
+
+func TestVideoDetailsUnmarshal(t *testing.T) {
+	body := `{
+		"status": true,
+		"id": "abc123",
+		"title": "a video",
+		"lengthSeconds": "125",
+		"adaptiveFormats": [
+			{"itag": 140, "mimeType": "audio/mp4", "bitrate": 128000},
+			{"itag": 137, "mimeType": "video/mp4", "bitrate": 4000000, "height": 1080}
+		]
+	}`
+
+	var resp getVideoDetailsResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if resp.LengthSeconds != 125 {
+		t.Errorf("LengthSeconds = %d, want 125", resp.LengthSeconds)
+	}
+	if len(resp.AdaptiveFormats) != 2 {
+		t.Fatalf("AdaptiveFormats = %d, want 2", len(resp.AdaptiveFormats))
+	}
+}
+
+func TestSelectBestAudio(t *testing.T) {
+	details := &VideoDetails{
+		AdaptiveFormats: []Format{
+			{MimeType: "video/mp4", Bitrate: 9000000},
+			{MimeType: "audio/mp4", Bitrate: 64000},
+			{MimeType: "audio/webm", Bitrate: 128000},
+		},
+	}
+
+	best, ok := SelectBestAudio(details)
+	if !ok {
+		t.Fatal("SelectBestAudio: ok = false, want true")
+	}
+	if best.MimeType != "audio/webm" || best.Bitrate != 128000 {
+		t.Errorf("got %+v, want the 128000bps audio/webm format", best)
+	}
+}
+
+func TestSelectBestAudioNoneFound(t *testing.T) {
+	details := &VideoDetails{AdaptiveFormats: []Format{{MimeType: "video/mp4"}}}
+
+	if _, ok := SelectBestAudio(details); ok {
+		t.Error("SelectBestAudio: ok = true, want false")
+	}
+}
+
+func TestSelectBestVideoPrefersHeightThenBitrate(t *testing.T) {
+	details := &VideoDetails{
+		AdaptiveFormats: []Format{
+			{MimeType: "video/mp4", Height: 720, Bitrate: 2000000},
+			{MimeType: "video/mp4", Height: 1080, Bitrate: 4000000},
+			{MimeType: "video/mp4", Height: 1080, Bitrate: 5000000},
+			{MimeType: "audio/mp4", Bitrate: 9999999},
+		},
+	}
+
+	best, ok := SelectBestVideo(details, 0)
+	if !ok {
+		t.Fatal("SelectBestVideo: ok = false, want true")
+	}
+	if best.Height != 1080 || best.Bitrate != 5000000 {
+		t.Errorf("got %+v, want 1080p @ 5000000bps (tie broken by bitrate)", best)
+	}
+}
+
+func TestSelectBestVideoRespectsMaxHeight(t *testing.T) {
+	details := &VideoDetails{
+		AdaptiveFormats: []Format{
+			{MimeType: "video/mp4", Height: 1080, Bitrate: 5000000},
+			{MimeType: "video/mp4", Height: 480, Bitrate: 1000000},
+		},
+	}
+
+	best, ok := SelectBestVideo(details, 720)
+	if !ok {
+		t.Fatal("SelectBestVideo: ok = false, want true")
+	}
+	if best.Height != 480 {
+		t.Errorf("got height %d, want 480 (1080p exceeds maxHeight)", best.Height)
+	}
+}
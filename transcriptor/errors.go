@@ -0,0 +1,37 @@
+package yttranscriptor
+
+import (
+	"errors"
+
+	"github.com/bjornpagen/youtube-apis/internal/httpx"
+)
+
+// Sentinel errors returned by TranscriptSource implementations and by
+// Client, so callers can branch with errors.Is instead of matching on error
+// strings. The ones shared with mediadownloader are aliases of the httpx
+// package's errors, so a single errors.Is check works regardless of which
+// client produced the error.
+var (
+	// ErrNoCaptions indicates the video has no captions in the requested
+	// language.
+	ErrNoCaptions = errors.New("yttranscriptor: no captions available")
+
+	// ErrRateLimited indicates the source is temporarily rate limiting
+	// requests.
+	ErrRateLimited = httpx.ErrRateLimited
+
+	// ErrNotFound indicates the video (or its captions) could not be found.
+	ErrNotFound = httpx.ErrNotFound
+
+	// ErrQuotaExceeded indicates the source's usage quota has been
+	// exhausted.
+	ErrQuotaExceeded = httpx.ErrQuotaExceeded
+
+	// ErrTransient indicates a retryable failure, such as a network error
+	// or a 5xx response.
+	ErrTransient = httpx.ErrTransient
+
+	// ErrPermanent indicates a non-retryable failure other than the cases
+	// above.
+	ErrPermanent = httpx.ErrPermanent
+)
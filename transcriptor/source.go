@@ -0,0 +1,192 @@
+package yttranscriptor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/ratelimit"
+)
+
+// TranscriptSource is anything that can fetch a transcript for a video. It
+// lets callers fall back between providers (the RapidAPI-backed Client,
+// yt-dlp, YouTube's public timedtext endpoint, ...) behind one interface.
+type TranscriptSource interface {
+	GetTranscript(videoID, lang string) (*GetTranscriptResponse, error)
+}
+
+// apiSource adapts Client to TranscriptSource.
+type apiSource struct {
+	client *Client
+}
+
+// NewAPISource wraps client as a TranscriptSource.
+func NewAPISource(client *Client) TranscriptSource {
+	return &apiSource{client: client}
+}
+
+func (s *apiSource) GetTranscript(videoID, lang string) (*GetTranscriptResponse, error) {
+	if lang == "" {
+		return s.client.GetTranscript(videoID)
+	}
+	return s.client.GetTranscript(videoID, WithLang(lang))
+}
+
+// YTDLPSource fetches transcripts by shelling out to yt-dlp for a video's
+// auto-generated captions. It requires yt-dlp to be installed and on PATH
+// (or at BinPath).
+type YTDLPSource struct {
+	// BinPath is the path to the yt-dlp executable. Defaults to "yt-dlp",
+	// resolved via PATH.
+	BinPath string
+}
+
+// NewYTDLPSource returns a YTDLPSource that resolves yt-dlp via PATH.
+func NewYTDLPSource() *YTDLPSource {
+	return &YTDLPSource{BinPath: "yt-dlp"}
+}
+
+func (s *YTDLPSource) GetTranscript(videoID, lang string) (*GetTranscriptResponse, error) {
+	if lang == "" {
+		lang = "en"
+	}
+
+	dir, err := os.MkdirTemp("", "yttranscriptor-ytdlp-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bin := s.BinPath
+	if bin == "" {
+		bin = "yt-dlp"
+	}
+
+	cmd := exec.Command(bin,
+		"--write-auto-sub",
+		"--sub-lang", lang,
+		"--skip-download",
+		"--sub-format", "json3",
+		"-o", filepath.Join(dir, "%(id)s"),
+		fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoID),
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, classifyYTDLPError(stderr.String())
+	}
+
+	subPath := filepath.Join(dir, fmt.Sprintf("%s.%s.json3", videoID, lang))
+	data, err := os.ReadFile(subPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoCaptions
+		}
+		return nil, fmt.Errorf("failed to read subtitle file: %w", err)
+	}
+
+	return parseJSON3(data)
+}
+
+type json3Document struct {
+	Events []struct {
+		TStartMs    int `json:"tStartMs"`
+		DDurationMs int `json:"dDurationMs"`
+		Segs        []struct {
+			Utf8 string `json:"utf8"`
+		} `json:"segs"`
+	} `json:"events"`
+}
+
+func parseJSON3(data []byte) (*GetTranscriptResponse, error) {
+	var doc json3Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json3 subtitles: %w", err)
+	}
+
+	resp := &GetTranscriptResponse{}
+	for _, e := range doc.Events {
+		var text string
+		for _, seg := range e.Segs {
+			text += seg.Utf8
+		}
+		if text == "" {
+			continue
+		}
+
+		resp.Transcription = append(resp.Transcription, Transcription{
+			Subtitle: text,
+			Start:    float64(e.TStartMs) / 1000,
+			Dur:      float64(e.DDurationMs) / 1000,
+		})
+	}
+
+	return resp, nil
+}
+
+func classifyYTDLPError(stderr string) error {
+	lower := strings.ToLower(stderr)
+	switch {
+	case strings.Contains(lower, "no subtitles"), strings.Contains(lower, "no automatic captions"):
+		return ErrNoCaptions
+	case strings.Contains(lower, "429"), strings.Contains(lower, "too many requests"):
+		return ErrRateLimited
+	case strings.Contains(lower, "sign in to confirm"), strings.Contains(lower, "quota"):
+		return ErrQuotaExceeded
+	default:
+		return fmt.Errorf("%w: yt-dlp failed: %s", ErrTransient, strings.TrimSpace(stderr))
+	}
+}
+
+// rateLimitedSource wraps a TranscriptSource with its own rate limit,
+// independent of any rate limit the underlying source applies to itself.
+type rateLimitedSource struct {
+	TranscriptSource
+	rateLimit ratelimit.Limiter
+}
+
+// NewRateLimitedSource wraps source so every call to GetTranscript first
+// takes a token from rl.
+func NewRateLimitedSource(source TranscriptSource, rl ratelimit.Limiter) TranscriptSource {
+	return &rateLimitedSource{TranscriptSource: source, rateLimit: rl}
+}
+
+func (s *rateLimitedSource) GetTranscript(videoID, lang string) (*GetTranscriptResponse, error) {
+	s.rateLimit.Take()
+	return s.TranscriptSource.GetTranscript(videoID, lang)
+}
+
+// ChainedSource tries each of its sources in order, falling through to the
+// next on error. This lets callers keep working when one provider is
+// rate-limited, out of quota, or down.
+type ChainedSource struct {
+	sources []TranscriptSource
+}
+
+// NewChainedSource returns a ChainedSource that tries sources in order.
+func NewChainedSource(sources ...TranscriptSource) *ChainedSource {
+	return &ChainedSource{sources: sources}
+}
+
+func (c *ChainedSource) GetTranscript(videoID, lang string) (*GetTranscriptResponse, error) {
+	var errs []error
+	for _, s := range c.sources {
+		resp, err := s.GetTranscript(videoID, lang)
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil, errors.New("yttranscriptor: no sources configured")
+	}
+
+	return nil, fmt.Errorf("all transcript sources failed: %w", errors.Join(errs...))
+}
@@ -0,0 +1,123 @@
+package yttranscriptor
+
+import (
+	"errors"
+	"testing"
+)
+
+type stubSource struct {
+	resp *GetTranscriptResponse
+	err  error
+}
+
+func (s *stubSource) GetTranscript(videoID, lang string) (*GetTranscriptResponse, error) {
+	return s.resp, s.err
+}
+
+func TestChainedSourceReturnsFirstSuccess(t *testing.T) {
+	want := &GetTranscriptResponse{Title: "from first"}
+	chain := NewChainedSource(
+		&stubSource{resp: want},
+		&stubSource{err: errors.New("should not be called")},
+	)
+
+	got, err := chain.GetTranscript("vid", "en")
+	if err != nil {
+		t.Fatalf("GetTranscript: %v", err)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestChainedSourceFallsThroughOnError(t *testing.T) {
+	want := &GetTranscriptResponse{Title: "from second"}
+	chain := NewChainedSource(
+		&stubSource{err: ErrQuotaExceeded},
+		&stubSource{resp: want},
+	)
+
+	got, err := chain.GetTranscript("vid", "en")
+	if err != nil {
+		t.Fatalf("GetTranscript: %v", err)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestChainedSourceJoinsErrorsWhenAllFail(t *testing.T) {
+	chain := NewChainedSource(
+		&stubSource{err: ErrNoCaptions},
+		&stubSource{err: ErrQuotaExceeded},
+	)
+
+	_, err := chain.GetTranscript("vid", "en")
+	if !errors.Is(err, ErrNoCaptions) {
+		t.Errorf("err does not wrap ErrNoCaptions: %v", err)
+	}
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("err does not wrap ErrQuotaExceeded: %v", err)
+	}
+}
+
+func TestChainedSourceNoSourcesConfigured(t *testing.T) {
+	chain := NewChainedSource()
+
+	if _, err := chain.GetTranscript("vid", "en"); err == nil {
+		t.Fatal("GetTranscript: err = nil, want an error")
+	}
+}
+
+func TestParseJSON3MultiSegmentCues(t *testing.T) {
+	data := []byte(`{"events":[
+		{"tStartMs":0,"dDurationMs":1000,"segs":[{"utf8":"hello "},{"utf8":"world"}]},
+		{"tStartMs":1000,"dDurationMs":500,"segs":[]},
+		{"tStartMs":2000,"dDurationMs":1500,"segs":[{"utf8":"bye"}]}
+	]}`)
+
+	resp, err := parseJSON3(data)
+	if err != nil {
+		t.Fatalf("parseJSON3: %v", err)
+	}
+
+	want := []Transcription{
+		{Subtitle: "hello world", Start: 0, Dur: 1},
+		{Subtitle: "bye", Start: 2, Dur: 1.5},
+	}
+	if len(resp.Transcription) != len(want) {
+		t.Fatalf("got %+v, want %+v", resp.Transcription, want)
+	}
+	for i := range want {
+		if resp.Transcription[i] != want[i] {
+			t.Errorf("cue %d = %+v, want %+v", i, resp.Transcription[i], want[i])
+		}
+	}
+}
+
+func TestParseJSON3InvalidJSON(t *testing.T) {
+	if _, err := parseJSON3([]byte("not json")); err == nil {
+		t.Fatal("parseJSON3: err = nil, want an error")
+	}
+}
+
+func TestClassifyYTDLPError(t *testing.T) {
+	cases := []struct {
+		stderr  string
+		wantErr error
+	}{
+		{"ERROR: no subtitles found", ErrNoCaptions},
+		{"ERROR: no automatic captions", ErrNoCaptions},
+		{"HTTP Error 429: Too Many Requests", ErrRateLimited},
+		{"ERROR: Sign in to confirm you're not a bot", ErrQuotaExceeded},
+		{"ERROR: quota exceeded", ErrQuotaExceeded},
+		{"some other failure", ErrTransient},
+	}
+
+	for _, tc := range cases {
+		got := classifyYTDLPError(tc.stderr)
+		if !errors.Is(got, tc.wantErr) {
+			t.Errorf("classifyYTDLPError(%q) = %v, want wrapping %v", tc.stderr, got, tc.wantErr)
+		}
+	}
+}
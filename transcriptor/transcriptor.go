@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/bjornpagen/youtube-apis/internal/cache"
+	"github.com/bjornpagen/youtube-apis/internal/httpx"
 	"go.uber.org/ratelimit"
 )
 
@@ -16,6 +20,10 @@ type options struct {
 	host       string
 	rateLimit  *ratelimit.Limiter
 	httpClient *http.Client
+	maxRetries int
+	logger     httpx.Logger
+	cache      cache.Cache
+	cacheTTL   time.Duration
 }
 
 func WithHost(host string) Option {
@@ -45,6 +53,39 @@ func WithHttpClient(hc http.Client) Option {
 	}
 }
 
+// WithMaxRetries sets how many times a request is retried after a 429/5xx
+// response or a network timeout, with exponential backoff and jitter
+// between attempts (and Retry-After honored where the response provides it).
+// An n of 0 leaves the default of 3 retries in place.
+func WithMaxRetries(n int) Option {
+	return func(option *options) error {
+		option.maxRetries = n
+		return nil
+	}
+}
+
+// WithLogger sets a logger to receive a line per retry attempt. *log.Logger
+// satisfies this interface.
+func WithLogger(logger httpx.Logger) Option {
+	return func(option *options) error {
+		option.logger = logger
+		return nil
+	}
+}
+
+// WithCache makes GetTranscript serve fresh entries from c for up to ttl
+// before re-fetching, using If-None-Match to revalidate stale entries
+// cheaply when the server supports it. Transcripts are idempotent per
+// (videoID, lang), so this can slash RapidAPI quota usage for callers that
+// repeatedly hit the same videos.
+func WithCache(c cache.Cache, ttl time.Duration) Option {
+	return func(option *options) error {
+		option.cache = c
+		option.cacheTTL = ttl
+		return nil
+	}
+}
+
 type Client struct {
 	apiKey  string
 	options *options
@@ -72,6 +113,10 @@ func New(apiKey string, opts ...Option) (*Client, error) {
 		o.httpClient = http.DefaultClient
 	}
 
+	if o.maxRetries == 0 {
+		o.maxRetries = 3
+	}
+
 	return &Client{
 		apiKey:  apiKey,
 		options: o,
@@ -115,6 +160,126 @@ func (g *GetTranscriptResponse) String() string {
 	return subtitles[:len(subtitles)-1]
 }
 
+// ToSRT writes the transcript to w as a SubRip (.srt) file.
+func (g *GetTranscriptResponse) ToSRT(w io.Writer) error {
+	for i, t := range g.Transcription {
+		_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n",
+			i+1,
+			formatSRTTimestamp(t.Start),
+			formatSRTTimestamp(t.Start+t.Dur),
+			t.Subtitle,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to write cue: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ToVTT writes the transcript to w as a WebVTT (.vtt) file.
+func (g *GetTranscriptResponse) ToVTT(w io.Writer) error {
+	if _, err := fmt.Fprint(w, "WEBVTT\n\n"); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, t := range g.Transcription {
+		_, err := fmt.Fprintf(w, "%s --> %s\n%s\n\n",
+			formatVTTTimestamp(t.Start),
+			formatVTTTimestamp(t.Start+t.Dur),
+			t.Subtitle,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to write cue: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// json3Response is the subset of YouTube's json3 timedtext format that we emit.
+type json3Response struct {
+	Events []json3Event `json:"events"`
+}
+
+type json3Event struct {
+	TStartMs    int        `json:"tStartMs"`
+	DDurationMs int        `json:"dDurationMs"`
+	Segs        []json3Seg `json:"segs"`
+}
+
+type json3Seg struct {
+	Utf8 string `json:"utf8"`
+}
+
+// ToJSON3 writes the transcript to w in YouTube's json3 timedtext format.
+func (g *GetTranscriptResponse) ToJSON3(w io.Writer) error {
+	events := make([]json3Event, len(g.Transcription))
+	for i, t := range g.Transcription {
+		events[i] = json3Event{
+			TStartMs:    int(t.Start * 1000),
+			DDurationMs: int(t.Dur * 1000),
+			Segs:        []json3Seg{{Utf8: t.Subtitle}},
+		}
+	}
+
+	return json.NewEncoder(w).Encode(json3Response{Events: events})
+}
+
+// Slice returns a copy of g containing only the cues that overlap
+// [startSec, endSec).
+func (g *GetTranscriptResponse) Slice(startSec, endSec float64) *GetTranscriptResponse {
+	out := *g
+	out.Transcription = nil
+
+	for _, t := range g.Transcription {
+		if t.Start+t.Dur <= startSec || t.Start >= endSec {
+			continue
+		}
+		out.Transcription = append(out.Transcription, t)
+	}
+
+	return &out
+}
+
+// Chunks splits the transcript into text windows of at most maxChars runes,
+// breaking on cue boundaries. A single cue longer than maxChars is not split:
+// it forms a chunk of its own that exceeds maxChars. It is intended for
+// feeding transcripts into LLM or embedding pipelines with bounded context
+// windows.
+func (g *GetTranscriptResponse) Chunks(maxChars int) []string {
+	if maxChars <= 0 {
+		return nil
+	}
+
+	var chunks []string
+	var b strings.Builder
+	runeLen := 0
+
+	for _, t := range g.Transcription {
+		n := utf8.RuneCountInString(t.Subtitle)
+
+		if runeLen > 0 && runeLen+n+1 > maxChars {
+			chunks = append(chunks, strings.TrimSpace(b.String()))
+			b.Reset()
+			runeLen = 0
+		}
+
+		if runeLen > 0 {
+			b.WriteByte(' ')
+			runeLen++
+		}
+		b.WriteString(t.Subtitle)
+		runeLen += n
+	}
+
+	if runeLen > 0 {
+		chunks = append(chunks, strings.TrimSpace(b.String()))
+	}
+
+	return chunks
+}
+
 type Thumbnail struct {
 	URL    string `json:"url"`
 	Width  int    `json:"width"`
@@ -127,6 +292,32 @@ type Transcription struct {
 	Dur      float64 `json:"dur"`
 }
 
+// formatSRTTimestamp formats seconds as an SRT timestamp: HH:MM:SS,mmm.
+func formatSRTTimestamp(seconds float64) string {
+	return formatTimestamp(seconds, ",")
+}
+
+// formatVTTTimestamp formats seconds as a WebVTT timestamp: HH:MM:SS.mmm.
+func formatVTTTimestamp(seconds float64) string {
+	return formatTimestamp(seconds, ".")
+}
+
+func formatTimestamp(seconds float64, msSep string) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	ms := int64(seconds*1000 + 0.5)
+	h := ms / 3600000
+	ms -= h * 3600000
+	m := ms / 60000
+	ms -= m * 60000
+	s := ms / 1000
+	ms -= s * 1000
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, msSep, ms)
+}
+
 func (c *Client) GetTranscript(videoID string, opts ...getTranscriptOption) (*GetTranscriptResponse, error) {
 	o := &getTranscriptOptions{}
 	for _, opt := range opts {
@@ -142,6 +333,15 @@ func (c *Client) GetTranscript(videoID string, opts ...getTranscriptOption) (*Ge
 
 	url := fmt.Sprintf("https://%s/transcript?video_id=%s&lang=%s", c.options.host, videoID, o.lang)
 
+	if c.options.cache != nil {
+		if val, expiresAt, ok := c.options.cache.Get(url); ok && time.Now().Before(expiresAt) {
+			var transcript GetTranscriptResponse
+			if err := json.Unmarshal(val, &transcript); err == nil {
+				return &transcript, nil
+			}
+		}
+	}
+
 	(*c.options.rateLimit).Take()
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -151,19 +351,35 @@ func (c *Client) GetTranscript(videoID string, opts ...getTranscriptOption) (*Ge
 	req.Header.Add("X-RapidAPI-Key", c.apiKey)
 	req.Header.Add("X-RapidAPI-Host", c.options.host)
 
-	res, err := c.options.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	var notModifiedBody []byte
+	if c.options.cache != nil {
+		if etag, _, ok := c.options.cache.Get(url + "#etag"); ok {
+			req.Header.Set("If-None-Match", string(etag))
+			notModifiedBody, _, _ = c.options.cache.Get(url)
+		}
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
+	var respHeader http.Header
+	body, err := httpx.Do(c.options.httpClient, req, httpx.Config{
+		MaxRetries:      c.options.maxRetries,
+		Logger:          c.options.logger,
+		NotModifiedBody: notModifiedBody,
+		ResponseHeader:  &respHeader,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status code is not ok: %s", string(body))
+	if c.options.cache != nil {
+		expiresAt := time.Now().Add(c.options.cacheTTL)
+		if err := c.options.cache.Put(url, body, expiresAt); err != nil {
+			httpx.Logf(c.options.logger, "yttranscriptor: cache put failed: %v", err)
+		}
+		if etag := respHeader.Get("ETag"); etag != "" {
+			if err := c.options.cache.Put(url+"#etag", []byte(etag), expiresAt); err != nil {
+				httpx.Logf(c.options.logger, "yttranscriptor: cache put failed: %v", err)
+			}
+		}
 	}
 
 	var transcript GetTranscriptResponse
@@ -0,0 +1,139 @@
+package yttranscriptor
+
+import (
+	"strings"
+	"testing"
+)
+
+func sampleTranscript() *GetTranscriptResponse {
+	return &GetTranscriptResponse{
+		Transcription: []Transcription{
+			{Subtitle: "hello", Start: 0, Dur: 1.5},
+			{Subtitle: "world", Start: 1.5, Dur: 2},
+			{Subtitle: "bye", Start: 10, Dur: 1},
+		},
+	}
+}
+
+func TestToSRT(t *testing.T) {
+	var b strings.Builder
+	if err := sampleTranscript().ToSRT(&b); err != nil {
+		t.Fatalf("ToSRT: %v", err)
+	}
+
+	want := "1\n00:00:00,000 --> 00:00:01,500\nhello\n\n" +
+		"2\n00:00:01,500 --> 00:00:03,500\nworld\n\n" +
+		"3\n00:00:10,000 --> 00:00:11,000\nbye\n\n"
+	if b.String() != want {
+		t.Errorf("ToSRT =\n%q\nwant\n%q", b.String(), want)
+	}
+}
+
+func TestToVTT(t *testing.T) {
+	var b strings.Builder
+	if err := sampleTranscript().ToVTT(&b); err != nil {
+		t.Fatalf("ToVTT: %v", err)
+	}
+
+	want := "WEBVTT\n\n" +
+		"00:00:00.000 --> 00:00:01.500\nhello\n\n" +
+		"00:00:01.500 --> 00:00:03.500\nworld\n\n" +
+		"00:00:10.000 --> 00:00:11.000\nbye\n\n"
+	if b.String() != want {
+		t.Errorf("ToVTT =\n%q\nwant\n%q", b.String(), want)
+	}
+}
+
+func TestToJSON3(t *testing.T) {
+	var b strings.Builder
+	if err := sampleTranscript().ToJSON3(&b); err != nil {
+		t.Fatalf("ToJSON3: %v", err)
+	}
+
+	want := `{"events":[` +
+		`{"tStartMs":0,"dDurationMs":1500,"segs":[{"utf8":"hello"}]},` +
+		`{"tStartMs":1500,"dDurationMs":2000,"segs":[{"utf8":"world"}]},` +
+		`{"tStartMs":10000,"dDurationMs":1000,"segs":[{"utf8":"bye"}]}` +
+		"]}\n"
+	if b.String() != want {
+		t.Errorf("ToJSON3 =\n%q\nwant\n%q", b.String(), want)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	cases := []struct {
+		name             string
+		startSec, endSec float64
+		want             []string
+	}{
+		{"covers all", 0, 100, []string{"hello", "world", "bye"}},
+		{"exact cue boundary excluded", 1.5, 3.5, []string{"world"}},
+		{"exclusive end drops adjacent cue", 0, 1.5, []string{"hello"}},
+		{"no overlap", 20, 30, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := sampleTranscript().Slice(tc.startSec, tc.endSec)
+
+			var subs []string
+			for _, tr := range got.Transcription {
+				subs = append(subs, tr.Subtitle)
+			}
+
+			if len(subs) != len(tc.want) {
+				t.Fatalf("got %v, want %v", subs, tc.want)
+			}
+			for i := range subs {
+				if subs[i] != tc.want[i] {
+					t.Errorf("got %v, want %v", subs, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestChunksWrapsOnMaxChars(t *testing.T) {
+	g := &GetTranscriptResponse{
+		Transcription: []Transcription{
+			{Subtitle: "one"},
+			{Subtitle: "two"},
+			{Subtitle: "three"},
+		},
+	}
+
+	// "one two" is 7 runes; adding " three" would be 13, over maxChars.
+	got := g.Chunks(10)
+	want := []string{"one two", "three"}
+
+	if len(got) != len(want) {
+		t.Fatalf("Chunks = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Chunks[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestChunksCountsRunesNotBytes(t *testing.T) {
+	// "日本 語" is 4 runes but 10 bytes; a byte-based bound would split the
+	// two cues into separate chunks at maxChars=5.
+	g := &GetTranscriptResponse{
+		Transcription: []Transcription{
+			{Subtitle: "日本"},
+			{Subtitle: "語"},
+		},
+	}
+
+	got := g.Chunks(5)
+	if len(got) != 1 || got[0] != "日本 語" {
+		t.Errorf("Chunks = %v, want [日本 語]", got)
+	}
+}
+
+func TestChunksZeroMaxChars(t *testing.T) {
+	if got := sampleTranscript().Chunks(0); got != nil {
+		t.Errorf("Chunks(0) = %v, want nil", got)
+	}
+}